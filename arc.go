@@ -0,0 +1,116 @@
+package raster
+
+import "math"
+
+// ArcTo renders an elliptical arc, centered at (cx,cy) with radii
+// (rx,ry), starting at angle startRad and sweeping through sweepRad
+// (both in radians, measured before the rotationRad tilt of the
+// ellipse is applied), as a sequence of cubic Bezier curves. Like
+// CubeTo and QuadTo, it assumes the pen is already at the arc's start
+// point and never moves it itself; a standalone caller should issue
+// its own MoveTo to that point first.
+func ArcTo(r Scriber, cx, cy, rx, ry, startRad, sweepRad, rotationRad float64) {
+	if sweepRad == 0 {
+		return
+	}
+	n := int(math.Ceil(math.Abs(sweepRad) / (math.Pi / 2)))
+	if n < 1 {
+		n = 1
+	}
+	theta := sweepRad / float64(n)
+	k := 4.0 / 3.0 * math.Tan(theta/4)
+	cosR, sinR := math.Cos(rotationRad), math.Sin(rotationRad)
+
+	place := func(subStart, x, y float64) (float64, float64) {
+		cs, sn := math.Cos(subStart), math.Sin(subStart)
+		xr, yr := x*cs-y*sn, x*sn+y*cs
+		xs, ys := xr*rx, yr*ry
+		xf := xs*cosR - ys*sinR
+		yf := xs*sinR + ys*cosR
+		return xf + cx, yf + cy
+	}
+
+	cosT, sinT := math.Cos(theta), math.Sin(theta)
+	for i := 0; i < n; i++ {
+		subStart := startRad + float64(i)*theta
+		x1, y1 := place(subStart, 1, k)
+		x2, y2 := place(subStart, cosT+k*sinT, sinT-k*cosT)
+		x3, y3 := place(subStart, cosT, sinT)
+		r.CubeTo(x1, y1, x2, y2, x3, y3)
+	}
+}
+
+// angleBetween returns the signed angle, in radians, from vector
+// (ux,uy) to vector (vx,vy).
+func angleBetween(ux, uy, vx, vy float64) float64 {
+	sign := 1.0
+	if ux*vy-uy*vx < 0 {
+		sign = -1
+	}
+	dot := ux*vx + uy*vy
+	lu := math.Hypot(ux, uy)
+	lv := math.Hypot(vx, vy)
+	cos := dot / (lu * lv)
+	if cos > 1 {
+		cos = 1
+	} else if cos < -1 {
+		cos = -1
+	}
+	return sign * math.Acos(cos)
+}
+
+// ArcToEndpoint renders the elliptical arc described in the SVG path
+// "A" command's endpoint parameterization: an arc of radii (rx,ry),
+// tilted by xAxisRotDeg degrees, from the current point (x1,y1) to
+// (x2,y2), choosing among the (up to four) arcs that connect those
+// two points via largeArc and sweepFlag as SVG 1.1 section F.6.5
+// describes. It converts to center parameterization and delegates to
+// ArcTo.
+func ArcToEndpoint(r Scriber, x1, y1, rx, ry, xAxisRotDeg float64, largeArc, sweepFlag bool, x2, y2 float64) {
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 || (x1 == x2 && y1 == y2) {
+		r.LineTo(x2, y2)
+		return
+	}
+	phi := xAxisRotDeg * math.Pi / 180
+	cosP, sinP := math.Cos(phi), math.Sin(phi)
+
+	dx, dy := (x1-x2)/2, (y1-y2)/2
+	x1p := cosP*dx + sinP*dy
+	y1p := -sinP*dx + cosP*dy
+
+	lambda := (x1p*x1p)/(rx*rx) + (y1p*y1p)/(ry*ry)
+	if lambda > 1 {
+		s := math.Sqrt(lambda)
+		rx *= s
+		ry *= s
+	}
+
+	rx2, ry2 := rx*rx, ry*ry
+	num := rx2*ry2 - rx2*y1p*y1p - ry2*x1p*x1p
+	den := rx2*y1p*y1p + ry2*x1p*x1p
+	co := 0.0
+	if den != 0 && num > 0 {
+		co = math.Sqrt(num / den)
+	}
+	if largeArc == sweepFlag {
+		co = -co
+	}
+	cxp := co * (rx * y1p / ry)
+	cyp := co * (-ry * x1p / rx)
+
+	cx := cosP*cxp - sinP*cyp + (x1+x2)/2
+	cy := sinP*cxp + cosP*cyp + (y1+y2)/2
+
+	ux, uy := (x1p-cxp)/rx, (y1p-cyp)/ry
+	vx, vy := (-x1p-cxp)/rx, (-y1p-cyp)/ry
+	start := angleBetween(1, 0, ux, uy)
+	sweep := angleBetween(ux, uy, vx, vy)
+	if !sweepFlag && sweep > 0 {
+		sweep -= 2 * math.Pi
+	} else if sweepFlag && sweep < 0 {
+		sweep += 2 * math.Pi
+	}
+
+	ArcTo(r, cx, cy, rx, ry, start, sweep, phi)
+}
@@ -0,0 +1,128 @@
+package raster
+
+import "math"
+
+// Dash describes a dash pattern for stroking a path: Pattern is a
+// sequence of alternating "on" (drawn) and "off" (skipped) lengths
+// measured along the path, and Phase offsets the starting position
+// within that sequence. An empty Pattern, or one whose lengths sum to
+// zero, draws a solid (undashed) line.
+type Dash struct {
+	Pattern []float64
+	Phase   float64
+}
+
+// dashState is the position of the dash walk within d.Pattern: idx
+// names the current pattern entry, pos is the distance already
+// consumed within it, and on says whether that entry is drawn.
+type dashState struct {
+	idx int
+	pos float64
+	on  bool
+}
+
+// total returns the sum of d's positive pattern entries: the length
+// of one pass through the pattern, ignoring zero and negative entries
+// that contribute no length.
+func (d *Dash) total() float64 {
+	total := 0.0
+	for _, p := range d.Pattern {
+		if p > 0 {
+			total += p
+		}
+	}
+	return total
+}
+
+// start resolves d.Phase to the dashState at the beginning of a walk.
+func (d *Dash) start() dashState {
+	total := d.total()
+	if total <= 0 {
+		return dashState{on: true}
+	}
+	period := total
+	if len(d.Pattern)%2 == 1 {
+		period *= 2 // an odd-length pattern swaps on and off each pass
+	}
+	phase := math.Mod(d.Phase, period)
+	if phase < 0 {
+		phase += period
+	}
+	idx, on := 0, true
+	for {
+		if p := d.Pattern[idx]; p > 0 {
+			if phase < p {
+				break
+			}
+			phase -= p
+		}
+		idx = (idx + 1) % len(d.Pattern)
+		on = !on
+	}
+	return dashState{idx: idx, pos: phase, on: on}
+}
+
+// walk strokes the segment from (oX,oY) to (nX,nY) according to d and
+// the given starting state, emitting a raster.LineTo call, capped as
+// requested, for each "on" interval, and returns the state at the segment's end so a
+// caller can continue the pattern into the next segment.
+func (d *Dash) walk(r Scriber, capped bool, oX, oY, nX, nY, width float64, s dashState) dashState {
+	if d.total() <= 0 {
+		LineTo(r, capped, oX, oY, nX, nY, width)
+		return s
+	}
+	dX, dY := nX-oX, nY-oY
+	length := math.Hypot(dX, dY)
+	if length == 0 {
+		return s
+	}
+	ux, uy := dX/length, dY/length
+	travelled := 0.0
+	for travelled < length {
+		for d.Pattern[s.idx] <= 0 {
+			s.idx = (s.idx + 1) % len(d.Pattern)
+			s.on = !s.on
+			s.pos = 0
+		}
+		step := d.Pattern[s.idx] - s.pos
+		if travelled+step > length {
+			step = length - travelled
+		}
+		if s.on {
+			LineTo(r, capped,
+				oX+ux*travelled, oY+uy*travelled,
+				oX+ux*(travelled+step), oY+uy*(travelled+step),
+				width)
+		}
+		travelled += step
+		s.pos += step
+		if s.pos >= d.Pattern[s.idx] {
+			s.pos = 0
+			s.idx = (s.idx + 1) % len(d.Pattern)
+			s.on = !s.on
+		}
+	}
+	return s
+}
+
+// LineTo strokes the segment from (oX,oY) to (nX,nY) at the given
+// width, broken into dashes starting at d.Phase into d.Pattern. Each
+// "on" interval is drawn with the existing raster.LineTo, so capped
+// behaves the same way: it adds rounded end-caps to every dash.
+func (d *Dash) LineTo(r Scriber, capped bool, oX, oY, nX, nY, width float64) {
+	d.walk(r, capped, oX, oY, nX, nY, width, d.start())
+}
+
+// Path strokes the polyline pts at the given width, as a sequence of
+// dashed segments whose pattern position is carried continuously
+// across each interior vertex, so joins don't restart the dash
+// pattern.
+func (d *Dash) Path(r Scriber, capped bool, pts []struct{ X, Y float64 }, width float64) {
+	if len(pts) < 2 {
+		return
+	}
+	s := d.start()
+	for i := 0; i < len(pts)-1; i++ {
+		s = d.walk(r, capped, pts[i].X, pts[i].Y, pts[i+1].X, pts[i+1].Y, width, s)
+	}
+}
@@ -0,0 +1,315 @@
+package raster
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// pathScanner is a cursor over an SVG path data string.
+type pathScanner struct {
+	s   string
+	pos int
+}
+
+func (p *pathScanner) skipSpace() {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t', '\r', '\n', ',':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// isPathCommand reports whether c is one of the SVG path data command
+// letters.
+func isPathCommand(c byte) bool {
+	switch c {
+	case 'M', 'm', 'L', 'l', 'H', 'h', 'V', 'v',
+		'C', 'c', 'S', 's', 'Q', 'q', 'T', 't', 'A', 'a', 'Z', 'z':
+		return true
+	}
+	return false
+}
+
+// peekCommand returns the next command letter without consuming it,
+// if the scanner is positioned at one.
+func (p *pathScanner) peekCommand() (byte, bool) {
+	p.skipSpace()
+	if p.pos >= len(p.s) || !isPathCommand(p.s[p.pos]) {
+		return 0, false
+	}
+	return p.s[p.pos], true
+}
+
+func (p *pathScanner) eof() bool {
+	p.skipSpace()
+	return p.pos >= len(p.s)
+}
+
+// num scans a single floating point number, per the SVG grammar:
+// an optional sign, digits, an optional fractional part and an
+// optional exponent.
+func (p *pathScanner) num() (float64, error) {
+	p.skipSpace()
+	n := len(p.s)
+	start := p.pos
+	if p.pos < n && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+		p.pos++
+	}
+	digits := p.pos
+	for p.pos < n && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos < n && p.s[p.pos] == '.' {
+		p.pos++
+		for p.pos < n && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+	}
+	if p.pos == digits {
+		return 0, fmt.Errorf("raster: expected number at %q", p.s[start:])
+	}
+	if p.pos < n && (p.s[p.pos] == 'e' || p.s[p.pos] == 'E') {
+		save := p.pos
+		p.pos++
+		if p.pos < n && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+			p.pos++
+		}
+		expDigits := p.pos
+		for p.pos < n && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		if p.pos == expDigits {
+			p.pos = save // not actually an exponent
+		}
+	}
+	v, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("raster: invalid number %q: %v", p.s[start:p.pos], err)
+	}
+	return v, nil
+}
+
+// pair scans two comma/space separated numbers.
+func (p *pathScanner) pair() (x, y float64, err error) {
+	if x, err = p.num(); err != nil {
+		return 0, 0, err
+	}
+	if y, err = p.num(); err != nil {
+		return 0, 0, err
+	}
+	return x, y, nil
+}
+
+// flag scans a single SVG path flag: the literal digit 0 or 1.
+func (p *pathScanner) flag() (bool, error) {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return false, fmt.Errorf("raster: expected flag, got end of path data")
+	}
+	switch p.s[p.pos] {
+	case '0':
+		p.pos++
+		return false, nil
+	case '1':
+		p.pos++
+		return true, nil
+	}
+	return false, fmt.Errorf("raster: expected flag (0 or 1) at %q", p.s[p.pos:])
+}
+
+// ParsePath tokenizes the SVG path data string d and issues the
+// corresponding MoveTo/LineTo/CubeTo/QuadTo/ClosePath calls on r. It
+// supports the M/m, L/l, H/h, V/v, C/c, S/s, Q/q, T/t, A/a and Z/z
+// commands in both their absolute and relative forms, including the
+// implicit repetition of a command across consecutive coordinate
+// groups and the smooth-curve control point reflection used by S/s
+// and T/t. Because it only depends on the Scriber interface, it
+// drives both direct rendering and the entry-recording mode of
+// Rasterizer.
+func ParsePath(d string, r Scriber) error {
+	p := &pathScanner{s: d}
+	var curX, curY, startX, startY float64
+	var prevCX, prevCY float64
+	var havePrevCube, havePrevQuad bool
+	var cmd byte
+
+	for !p.eof() {
+		if c, ok := p.peekCommand(); ok {
+			cmd = c
+			p.pos++
+		} else if cmd == 0 {
+			return fmt.Errorf("raster: expected path command at %q", p.s[p.pos:])
+		}
+		rel := cmd >= 'a' && cmd <= 'z'
+		abs := func(x, y float64) (float64, float64) {
+			if rel {
+				return curX + x, curY + y
+			}
+			return x, y
+		}
+
+		switch cmd {
+		case 'M', 'm':
+			x, y, err := p.pair()
+			if err != nil {
+				return err
+			}
+			curX, curY = abs(x, y)
+			startX, startY = curX, curY
+			r.MoveTo(curX, curY)
+			havePrevCube, havePrevQuad = false, false
+			if rel {
+				cmd = 'l'
+			} else {
+				cmd = 'L'
+			}
+		case 'L', 'l':
+			x, y, err := p.pair()
+			if err != nil {
+				return err
+			}
+			curX, curY = abs(x, y)
+			r.LineTo(curX, curY)
+			havePrevCube, havePrevQuad = false, false
+		case 'H', 'h':
+			x, err := p.num()
+			if err != nil {
+				return err
+			}
+			if rel {
+				curX += x
+			} else {
+				curX = x
+			}
+			r.LineTo(curX, curY)
+			havePrevCube, havePrevQuad = false, false
+		case 'V', 'v':
+			y, err := p.num()
+			if err != nil {
+				return err
+			}
+			if rel {
+				curY += y
+			} else {
+				curY = y
+			}
+			r.LineTo(curX, curY)
+			havePrevCube, havePrevQuad = false, false
+		case 'C', 'c':
+			x1, y1, err := p.pair()
+			if err != nil {
+				return err
+			}
+			x2, y2, err := p.pair()
+			if err != nil {
+				return err
+			}
+			x, y, err := p.pair()
+			if err != nil {
+				return err
+			}
+			ax1, ay1 := abs(x1, y1)
+			ax2, ay2 := abs(x2, y2)
+			ax, ay := abs(x, y)
+			r.CubeTo(ax1, ay1, ax2, ay2, ax, ay)
+			curX, curY = ax, ay
+			prevCX, prevCY = ax2, ay2
+			havePrevCube, havePrevQuad = true, false
+		case 'S', 's':
+			x2, y2, err := p.pair()
+			if err != nil {
+				return err
+			}
+			x, y, err := p.pair()
+			if err != nil {
+				return err
+			}
+			ax2, ay2 := abs(x2, y2)
+			ax, ay := abs(x, y)
+			cx1, cy1 := curX, curY
+			if havePrevCube {
+				cx1, cy1 = 2*curX-prevCX, 2*curY-prevCY
+			}
+			r.CubeTo(cx1, cy1, ax2, ay2, ax, ay)
+			curX, curY = ax, ay
+			prevCX, prevCY = ax2, ay2
+			havePrevCube, havePrevQuad = true, false
+		case 'Q', 'q':
+			x1, y1, err := p.pair()
+			if err != nil {
+				return err
+			}
+			x, y, err := p.pair()
+			if err != nil {
+				return err
+			}
+			ax1, ay1 := abs(x1, y1)
+			ax, ay := abs(x, y)
+			r.QuadTo(ax1, ay1, ax, ay)
+			curX, curY = ax, ay
+			prevCX, prevCY = ax1, ay1
+			havePrevCube, havePrevQuad = false, true
+		case 'T', 't':
+			x, y, err := p.pair()
+			if err != nil {
+				return err
+			}
+			ax, ay := abs(x, y)
+			cx1, cy1 := curX, curY
+			if havePrevQuad {
+				cx1, cy1 = 2*curX-prevCX, 2*curY-prevCY
+			}
+			r.QuadTo(cx1, cy1, ax, ay)
+			curX, curY = ax, ay
+			prevCX, prevCY = cx1, cy1
+			havePrevCube, havePrevQuad = false, true
+		case 'A', 'a':
+			rx, err := p.num()
+			if err != nil {
+				return err
+			}
+			ry, err := p.num()
+			if err != nil {
+				return err
+			}
+			rot, err := p.num()
+			if err != nil {
+				return err
+			}
+			large, err := p.flag()
+			if err != nil {
+				return err
+			}
+			sweep, err := p.flag()
+			if err != nil {
+				return err
+			}
+			x, y, err := p.pair()
+			if err != nil {
+				return err
+			}
+			ax, ay := abs(x, y)
+			ArcToEndpoint(r, curX, curY, rx, ry, rot, large, sweep, ax, ay)
+			curX, curY = ax, ay
+			havePrevCube, havePrevQuad = false, false
+		case 'Z', 'z':
+			r.ClosePath()
+			curX, curY = startX, startY
+			havePrevCube, havePrevQuad = false, false
+			cmd = 0 // Z never repeats implicitly
+		default:
+			return fmt.Errorf("raster: unsupported path command %q", cmd)
+		}
+	}
+	return nil
+}
+
+// MustParsePath is like ParsePath but panics if d cannot be parsed.
+func MustParsePath(d string, r Scriber) {
+	if err := ParsePath(d, r); err != nil {
+		panic(err)
+	}
+}
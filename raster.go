@@ -17,6 +17,7 @@ type Scriber interface {
 	MoveTo(x, y float64)
 	LineTo(x, y float64)
 	CubeTo(a, b, c, d, e, f float64)
+	QuadTo(a, b, c, d float64)
 	ClosePath()
 }
 
@@ -27,6 +28,7 @@ const (
 	moveto
 	lineto
 	cubeto
+	quadto
 )
 
 func (op Operator) String() string {
@@ -37,6 +39,8 @@ func (op Operator) String() string {
 		return "LineTo"
 	case cubeto:
 		return "CubeTo"
+	case quadto:
+		return "QuadTo"
 	default:
 		return "invalid"
 	}
@@ -59,8 +63,13 @@ type Entry struct {
 // golang.org/x/image/vector.Rasterizer type which maps float64
 // arguments to float32 bit calls.
 type Rasterizer struct {
-	R       *vector.Rasterizer
-	Entries []Entry
+	R        *vector.Rasterizer
+	Entries  []Entry
+	xform    [6]float64
+	xformSet bool
+	stack    [][6]float64
+	penX     float64
+	penY     float64
 }
 
 // NewRasterizer allocates a new rasterizer with a fixed size.
@@ -92,26 +101,118 @@ func (r *Rasterizer) extend(op Operator, args ...float64) {
 		n++
 		i += 2
 	}
-	for ; i < len(args); i += 2 {
-		if a := args[i]; a < r.Entries[n].MinX {
-			r.Entries[n].MinX = a
-		} else if a > r.Entries[n].MaxX {
-			r.Entries[n].MaxX = a
-		}
-		if a := args[i+1]; a < r.Entries[n].MinY {
-			r.Entries[n].MinY = a
-		} else if a > r.Entries[n].MaxY {
-			r.Entries[n].MaxY = a
+	e := &r.Entries[n]
+	switch {
+	case op == cubeto && i == 0:
+		extendCubic(e, r.penX, r.penY, args[0], args[1], args[2], args[3], args[4], args[5])
+	case op == quadto && i == 0:
+		extendQuad(e, r.penX, r.penY, args[0], args[1], args[2], args[3])
+	default:
+		for ; i < len(args); i += 2 {
+			extendPoint(e, args[i], args[i+1])
 		}
 	}
-	r.Entries[n].Path = append(r.Entries[n].Path, Segment{
+	e.Path = append(e.Path, Segment{
 		Op:   op,
 		Args: args,
 	})
+	r.penX, r.penY = args[len(args)-2], args[len(args)-1]
+}
+
+// extendPoint expands e's bounding box to include the point (x,y).
+func extendPoint(e *Entry, x, y float64) {
+	if x < e.MinX {
+		e.MinX = x
+	} else if x > e.MaxX {
+		e.MaxX = x
+	}
+	if y < e.MinY {
+		e.MinY = y
+	} else if y > e.MaxY {
+		e.MaxY = y
+	}
+}
+
+// cubicRoots appends to ts the roots in the open interval (0,1) of the
+// derivative of the cubic Bezier with the given single-axis control
+// values p0..p3.
+func cubicRoots(ts []float64, p0, p1, p2, p3 float64) []float64 {
+	a := -p0 + 3*p1 - 3*p2 + p3
+	b := 2 * (p0 - 2*p1 + p2)
+	c := p1 - p0
+	if a == 0 {
+		if b == 0 {
+			return ts
+		}
+		if t := -c / b; t > 0 && t < 1 {
+			ts = append(ts, t)
+		}
+		return ts
+	}
+	disc := b*b - 4*a*c
+	if disc < 0 {
+		return ts
+	}
+	sq := math.Sqrt(disc)
+	if t := (-b + sq) / (2 * a); t > 0 && t < 1 {
+		ts = append(ts, t)
+	}
+	if t := (-b - sq) / (2 * a); t > 0 && t < 1 {
+		ts = append(ts, t)
+	}
+	return ts
+}
+
+func cubicAt(p0, p1, p2, p3, t float64) float64 {
+	mt := 1 - t
+	return mt*mt*mt*p0 + 3*mt*mt*t*p1 + 3*mt*t*t*p2 + t*t*t*p3
+}
+
+// extendCubic expands e's bounding box to include the true extent of
+// the cubic Bezier curve from (x0,y0), via control points (x1,y1) and
+// (x2,y2), to (x3,y3), rather than the (looser) bounds of its control
+// polygon.
+func extendCubic(e *Entry, x0, y0, x1, y1, x2, y2, x3, y3 float64) {
+	extendPoint(e, x3, y3)
+	for _, t := range cubicRoots(cubicRoots(nil, x0, x1, x2, x3), y0, y1, y2, y3) {
+		extendPoint(e, cubicAt(x0, x1, x2, x3, t), cubicAt(y0, y1, y2, y3, t))
+	}
 }
 
-// MoveTo sets the rasterizer pen to the coordinate (x,y).
+// quadRoot returns the root, if any, in the open interval (0,1) of
+// the derivative of the quadratic Bezier with single-axis control
+// values p0..p2.
+func quadRoot(p0, p1, p2 float64) (float64, bool) {
+	denom := p0 - 2*p1 + p2
+	if denom == 0 {
+		return 0, false
+	}
+	t := (p0 - p1) / denom
+	return t, t > 0 && t < 1
+}
+
+func quadAt(p0, p1, p2, t float64) float64 {
+	mt := 1 - t
+	return mt*mt*p0 + 2*mt*t*p1 + t*t*p2
+}
+
+// extendQuad expands e's bounding box to include the true extent of
+// the quadratic Bezier curve from (x0,y0), via control point (x1,y1),
+// to (x2,y2).
+func extendQuad(e *Entry, x0, y0, x1, y1, x2, y2 float64) {
+	extendPoint(e, x2, y2)
+	if t, ok := quadRoot(x0, x1, x2); ok {
+		extendPoint(e, quadAt(x0, x1, x2, t), quadAt(y0, y1, y2, t))
+	}
+	if t, ok := quadRoot(y0, y1, y2); ok {
+		extendPoint(e, quadAt(x0, x1, x2, t), quadAt(y0, y1, y2, t))
+	}
+}
+
+// MoveTo sets the rasterizer pen to the coordinate (x,y), mapped
+// through the current transform (see Push).
 func (r *Rasterizer) MoveTo(x, y float64) {
+	x, y = r.transform(x, y)
 	r.extend(moveto, x, y)
 	if r.R != nil {
 		r.R.MoveTo(float32(x), float32(y))
@@ -119,8 +220,10 @@ func (r *Rasterizer) MoveTo(x, y float64) {
 }
 
 // LineTo constructs a straight line from the pen to the target (x,y)
-// coordinate, and updates the pen to this location.
+// coordinate, mapped through the current transform (see Push), and
+// updates the pen to this location.
 func (r *Rasterizer) LineTo(x, y float64) {
+	x, y = r.transform(x, y)
 	r.extend(lineto, x, y)
 	if r.R != nil {
 		r.R.LineTo(float32(x), float32(y))
@@ -128,15 +231,32 @@ func (r *Rasterizer) LineTo(x, y float64) {
 }
 
 // CubeTo constructs a cubic Bezier curve using the supplied
-// parameters, from the pen location to point (e,f), which becomes the
-// updated pen location.
+// parameters, each mapped through the current transform (see Push),
+// from the pen location to point (e,f), which becomes the updated pen
+// location.
 func (r *Rasterizer) CubeTo(a, b, c, d, e, f float64) {
+	a, b = r.transform(a, b)
+	c, d = r.transform(c, d)
+	e, f = r.transform(e, f)
 	r.extend(cubeto, a, b, c, d, e, f)
 	if r.R != nil {
 		r.R.CubeTo(float32(a), float32(b), float32(c), float32(d), float32(e), float32(f))
 	}
 }
 
+// QuadTo constructs a quadratic Bezier curve using the supplied
+// control point (a,b), mapped through the current transform (see
+// Push), from the pen location to point (c,d), which becomes the
+// updated pen location.
+func (r *Rasterizer) QuadTo(a, b, c, d float64) {
+	a, b = r.transform(a, b)
+	c, d = r.transform(c, d)
+	r.extend(quadto, a, b, c, d)
+	if r.R != nil {
+		r.R.QuadTo(float32(a), float32(b), float32(c), float32(d))
+	}
+}
+
 // ClosePath forms a loop back line from the pen to the start of the
 // path.
 func (r *Rasterizer) ClosePath() {
@@ -224,21 +344,32 @@ func DrawAt(im draw.Image, r *vector.Rasterizer, x, y float64, col color.Color)
 // Render places the entries of r into the im at (x,y) offset.
 func (r *Rasterizer) Render(im draw.Image, x, y float64, col color.Color) {
 	for _, e := range r.Entries {
-		wide := int(2 + e.MaxX - e.MinX)
-		high := int(2 + e.MaxY - e.MinY)
-		vr := vector.NewRasterizer(wide, high)
 		if !e.Closed {
 			continue // empty shape
 		}
+		wide := int(math.Ceil(e.MaxX - e.MinX))
+		high := int(math.Ceil(e.MaxY - e.MinY))
+		if wide < 1 {
+			wide = 1
+		}
+		if high < 1 {
+			high = 1
+		}
+		vr := vector.NewRasterizer(wide, high)
 		toX := func(x float64) float32 {
-			return float32(1 + x - e.MinX)
+			return float32(x - e.MinX)
 		}
 		toY := func(y float64) float32 {
-			return float32(1 + y - e.MinY)
+			return float32(y - e.MinY)
 		}
 		for _, p := range e.Path {
 			a := p.Args
-			if (p.Op == cubeto && len(a) != 6) || (p.Op != cubeto && len(a) != 2) {
+			switch {
+			case p.Op == cubeto && len(a) != 6:
+				fallthrough
+			case p.Op == quadto && len(a) != 4:
+				fallthrough
+			case p.Op != cubeto && p.Op != quadto && len(a) != 2:
 				panic(fmt.Sprint("invalid arg count ", len(a), " for ", p.Op))
 			}
 			switch p.Op {
@@ -248,12 +379,14 @@ func (r *Rasterizer) Render(im draw.Image, x, y float64, col color.Color) {
 				vr.LineTo(toX(a[0]), toY(a[1]))
 			case cubeto:
 				vr.CubeTo(toX(a[0]), toY(a[1]), toX(a[2]), toY(a[3]), toX(a[4]), toY(a[5]))
+			case quadto:
+				vr.QuadTo(toX(a[0]), toY(a[1]), toX(a[2]), toY(a[3]))
 			default:
 				panic(fmt.Sprint("unsupported Op=", p.Op))
 			}
 		}
 		vr.ClosePath()
-		ix, iy := int(x+e.MinX-1), int(y+e.MinY-1)
+		ix, iy := int(x+e.MinX), int(y+e.MinY)
 		vr.Draw(im, image.Rect(ix, iy, ix+wide, iy+high), image.NewUniform(col), image.ZP)
 	}
 }
@@ -0,0 +1,236 @@
+package raster
+
+import "math"
+
+// Cap describes how the two open ends of a stroked path are
+// terminated.
+type Cap int
+
+const (
+	CapButt Cap = iota
+	CapSquare
+	CapRound
+)
+
+// Join describes how two consecutive segments of a stroked path are
+// connected at an interior vertex.
+type Join int
+
+const (
+	JoinMiter Join = iota
+	JoinRound
+	JoinBevel
+)
+
+// Stroke describes the parameters used to convert a polyline into a
+// single filled outline.
+type Stroke struct {
+	Width      float64
+	Cap        Cap
+	Join       Join
+	MiterLimit float64
+}
+
+// point is the internal representation of a 2D coordinate used by
+// the stroke geometry.
+type point struct {
+	X, Y float64
+}
+
+func add(a, b point) point           { return point{a.X + b.X, a.Y + b.Y} }
+func sub(a, b point) point           { return point{a.X - b.X, a.Y - b.Y} }
+func scale(a point, s float64) point { return point{a.X * s, a.Y * s} }
+
+// unit returns the unit length direction vector from a to b, or the
+// zero vector if a and b coincide.
+func unit(a, b point) point {
+	dX, dY := b.X-a.X, b.Y-a.Y
+	d := math.Hypot(dX, dY)
+	if d == 0 {
+		return point{}
+	}
+	return point{dX / d, dY / d}
+}
+
+// Path emits the single filled outline of the polyline pts, stroked
+// to s.Width with the configured Cap and Join, into r. When closed is
+// false, pts describes an open polyline and the result is a single
+// subpath capped at both ends. When closed is true, the first and
+// last points are implicitly connected and the result is a pair of
+// oppositely wound subpaths (an outer and an inner boundary) that,
+// under the non-zero winding rule, render as a single ring-shaped
+// outline.
+func (s *Stroke) Path(r Scriber, pts []struct{ X, Y float64 }, closed bool) {
+	n := len(pts)
+	if n < 2 || s.Width <= 0 {
+		return
+	}
+	half := 0.5 * s.Width
+	p := make([]point, n)
+	for i, q := range pts {
+		p[i] = point{q.X, q.Y}
+	}
+
+	if closed {
+		s.loop(r, p, half)
+		s.loop(r, reverse(p), half)
+		return
+	}
+
+	s.side(r, p, half, true)
+	s.cap(r, p[n-1], unit(p[n-2], p[n-1]), half)
+	s.side(r, reverse(p), half, false)
+	s.cap(r, p[0], unit(p[1], p[0]), half)
+	r.ClosePath()
+}
+
+// nrm returns the left-hand offset of magnitude half for the unit
+// direction vector d.
+func nrm(d point, half float64) point {
+	return point{-d.Y * half, d.X * half}
+}
+
+// side draws the left-hand offset boundary of the open polyline p
+// into r, moving the pen to the first point if move is true and
+// otherwise continuing the current subpath with a line.
+func (s *Stroke) side(r Scriber, p []point, half float64, move bool) {
+	n := len(p)
+	dir := make([]point, n-1)
+	for i := 0; i < n-1; i++ {
+		dir[i] = unit(p[i], p[i+1])
+	}
+	start := add(p[0], nrm(dir[0], half))
+	if move {
+		r.MoveTo(start.X, start.Y)
+	} else {
+		r.LineTo(start.X, start.Y)
+	}
+	for i := 1; i < n-1; i++ {
+		pin := add(p[i], nrm(dir[i-1], half))
+		r.LineTo(pin.X, pin.Y)
+		s.join(r, p[i], dir[i-1], dir[i], half)
+	}
+	end := add(p[n-1], nrm(dir[n-2], half))
+	r.LineTo(end.X, end.Y)
+}
+
+// loop draws a single closed offset boundary for the closed polyline
+// p, including the join at every vertex (the wrap-around join at
+// p[0] included), and closes the subpath.
+func (s *Stroke) loop(r Scriber, p []point, half float64) {
+	n := len(p)
+	dir := make([]point, n)
+	for i := 0; i < n; i++ {
+		dir[i] = unit(p[i], p[(i+1)%n])
+	}
+	start := add(p[0], nrm(dir[n-1], half))
+	r.MoveTo(start.X, start.Y)
+	for i := 0; i < n; i++ {
+		din := dir[(i-1+n)%n]
+		dout := dir[i]
+		s.join(r, p[i], din, dout, half)
+		if i < n-1 {
+			next := add(p[i+1], nrm(dout, half))
+			r.LineTo(next.X, next.Y)
+		}
+	}
+	r.ClosePath()
+}
+
+// join draws the transition between the incoming and outgoing offset
+// edges at vertex v, assuming the pen is already positioned at the
+// incoming edge's offset point, leaving the pen at the outgoing
+// edge's offset point.
+func (s *Stroke) join(r Scriber, v, din, dout point, half float64) {
+	pout := add(v, nrm(dout, half))
+	cross := din.X*dout.Y - din.Y*dout.X
+	if math.Abs(cross) < 1e-9 {
+		r.LineTo(pout.X, pout.Y)
+		return
+	}
+	switch s.Join {
+	case JoinRound:
+		pin := add(v, nrm(din, half))
+		arcJoin(r, v, pin, pout, half)
+	case JoinBevel:
+		r.LineTo(pout.X, pout.Y)
+	default: // JoinMiter
+		pin := add(v, nrm(din, half))
+		if m, ok := lineIntersect(pin, din, pout, dout); ok {
+			if math.Hypot(m.X-v.X, m.Y-v.Y) <= s.MiterLimit*half {
+				r.LineTo(m.X, m.Y)
+			}
+		}
+		r.LineTo(pout.X, pout.Y)
+	}
+}
+
+// lineIntersect finds the intersection of the line through p1 with
+// direction d1 and the line through p2 with direction d2. It returns
+// false if the lines are parallel.
+func lineIntersect(p1, d1, p2, d2 point) (point, bool) {
+	denom := d1.X*d2.Y - d1.Y*d2.X
+	if math.Abs(denom) < 1e-9 {
+		return point{}, false
+	}
+	t := ((p2.X-p1.X)*d2.Y - (p2.Y-p1.Y)*d2.X) / denom
+	return point{p1.X + t*d1.X, p1.Y + t*d1.Y}, true
+}
+
+// arcJoin approximates, with a single cubic Bezier curve, the arc of
+// radius half centered at c that connects p0 to p1, assuming the pen
+// is already at p0. The control point offset is the partial constant
+// (calibrated for a quarter circle) scaled by the arc's actual
+// fraction of a quarter circle.
+func arcJoin(r Scriber, c, p0, p1 point, half float64) {
+	a0 := math.Atan2(p0.Y-c.Y, p0.X-c.X)
+	a1 := math.Atan2(p1.Y-c.Y, p1.X-c.X)
+	d := a1 - a0
+	for d > math.Pi {
+		d -= 2 * math.Pi
+	}
+	for d < -math.Pi {
+		d += 2 * math.Pi
+	}
+	k := partial * half * (d / (math.Pi / 2))
+	t0 := point{-math.Sin(a0) * k, math.Cos(a0) * k}
+	t1 := point{-math.Sin(a1) * k, math.Cos(a1) * k}
+	r.CubeTo(p0.X+t0.X, p0.Y+t0.Y, p1.X-t1.X, p1.Y-t1.Y, p1.X, p1.Y)
+}
+
+// cap terminates an open subpath at vertex v, where dir is the unit
+// direction pointing away from the stroked path at that end. The pen
+// is assumed to be at v's left-hand offset (v + nrm(dir)); the cap
+// leaves the pen at v's right-hand offset (v - nrm(dir)).
+func (s *Stroke) cap(r Scriber, v, dir point, half float64) {
+	right := sub(v, nrm(dir, half))
+	switch s.Cap {
+	case CapButt:
+		r.LineTo(right.X, right.Y)
+	case CapSquare:
+		left := add(v, nrm(dir, half))
+		tip := scale(dir, half)
+		a := add(left, tip)
+		b := add(right, tip)
+		r.LineTo(a.X, a.Y)
+		r.LineTo(b.X, b.Y)
+		r.LineTo(right.X, right.Y)
+	case CapRound:
+		left := add(v, nrm(dir, half))
+		tip := add(v, scale(dir, half))
+		pd := scale(dir, half*partial)
+		pn := scale(nrm(dir, half), partial)
+		r.CubeTo(left.X+pd.X, left.Y+pd.Y, tip.X+pn.X, tip.Y+pn.Y, tip.X, tip.Y)
+		r.CubeTo(tip.X-pn.X, tip.Y-pn.Y, right.X+pd.X, right.Y+pd.Y, right.X, right.Y)
+	}
+}
+
+// reverse returns a new slice with the points of p in reverse order.
+func reverse(p []point) []point {
+	n := len(p)
+	q := make([]point, n)
+	for i, v := range p {
+		q[n-1-i] = v
+	}
+	return q
+}
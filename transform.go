@@ -0,0 +1,88 @@
+package raster
+
+import "math"
+
+// identity is the affine transform that leaves coordinates unchanged.
+var identity = [6]float64{1, 0, 0, 1, 0, 0}
+
+// current returns the transform in effect, treating the zero value of
+// Rasterizer (before any Push or Identity call) as the identity.
+func (r *Rasterizer) current() [6]float64 {
+	if !r.xformSet {
+		return identity
+	}
+	return r.xform
+}
+
+// transform maps (x,y) through the current transform.
+func (r *Rasterizer) transform(x, y float64) (float64, float64) {
+	m := r.current()
+	return m[0]*x + m[2]*y + m[4], m[1]*x + m[3]*y + m[5]
+}
+
+// compose returns the affine transform equivalent to first applying
+// m2 and then m1, both column-major matrices {a,b,c,d,e,f} mapping
+// (x,y) to (a*x+c*y+e, b*x+d*y+f), in the style of SVG's matrix().
+func compose(m1, m2 [6]float64) [6]float64 {
+	return [6]float64{
+		m1[0]*m2[0] + m1[2]*m2[1],
+		m1[1]*m2[0] + m1[3]*m2[1],
+		m1[0]*m2[2] + m1[2]*m2[3],
+		m1[1]*m2[2] + m1[3]*m2[3],
+		m1[0]*m2[4] + m1[2]*m2[5] + m1[4],
+		m1[1]*m2[4] + m1[3]*m2[5] + m1[5],
+	}
+}
+
+// Push concatenates the affine transform m onto the current
+// transform, and saves the previous transform so a matching Pop can
+// restore it. Coordinates passed to MoveTo/LineTo/CubeTo/QuadTo after
+// a Push are mapped through m in the space established by any
+// enclosing Push calls.
+func (r *Rasterizer) Push(m [6]float64) {
+	r.stack = append(r.stack, r.current())
+	r.xform = compose(r.current(), m)
+	r.xformSet = true
+}
+
+// Pop restores the transform in effect before the matching Push. It
+// is a no-op if the transform stack is empty.
+func (r *Rasterizer) Pop() {
+	n := len(r.stack)
+	if n == 0 {
+		return
+	}
+	r.xform = r.stack[n-1]
+	r.xformSet = true
+	r.stack = r.stack[:n-1]
+}
+
+// Identity resets the current transform to the identity, without
+// touching the Push/Pop stack.
+func (r *Rasterizer) Identity() {
+	r.xform = identity
+	r.xformSet = true
+}
+
+// Translate pushes a transform that offsets coordinates by (dx,dy).
+func (r *Rasterizer) Translate(dx, dy float64) {
+	r.Push([6]float64{1, 0, 0, 1, dx, dy})
+}
+
+// Scale pushes a transform that scales coordinates by (sx,sy).
+func (r *Rasterizer) Scale(sx, sy float64) {
+	r.Push([6]float64{sx, 0, 0, sy, 0, 0})
+}
+
+// Rotate pushes a transform that rotates coordinates by theta
+// radians, counter-clockwise.
+func (r *Rasterizer) Rotate(theta float64) {
+	c, s := math.Cos(theta), math.Sin(theta)
+	r.Push([6]float64{c, s, -s, c, 0, 0})
+}
+
+// Shear pushes a transform that shears coordinates by shx along the
+// X axis and shy along the Y axis.
+func (r *Rasterizer) Shear(shx, shy float64) {
+	r.Push([6]float64{1, shy, shx, 1, 0, 0})
+}